@@ -0,0 +1,75 @@
+// Command api serves the users HTTP API.
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	_ "github.com/lib/pq"
+
+	"github.com/wess-os/api_golang_pg_docker/internal/auth"
+	"github.com/wess-os/api_golang_pg_docker/internal/mail"
+	"github.com/wess-os/api_golang_pg_docker/migrations"
+	"github.com/wess-os/api_golang_pg_docker/pkg/controllers"
+	"github.com/wess-os/api_golang_pg_docker/pkg/repository"
+	"github.com/wess-os/api_golang_pg_docker/pkg/service"
+)
+
+func main() {
+	if os.Getenv("JWT_SECRET") == "" {
+		log.Fatal("JWT_SECRET must be set")
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+
+	// connect to database
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	// apply any pending schema migrations
+	if err := migrations.Up(databaseURL); err != nil {
+		log.Fatal(err)
+	}
+	log.Println("migrations applied")
+
+	repo := repository.NewPostgresUserRepo(db)
+	svc := service.NewUserService(repo)
+	mailer := mail.NewSMTPSenderFromEnv()
+	ctrl := controllers.NewUserController(svc, mailer, os.Getenv("APP_BASE_URL")+"/users/verify")
+
+	// create router
+	router := mux.NewRouter()
+	router.HandleFunc("/login", ctrl.Login).Methods("POST")
+	router.HandleFunc("/users/verify", ctrl.VerifyUser).Methods("GET")
+
+	users := router.PathPrefix("/users").Subrouter()
+	users.Use(auth.Middleware)
+	users.HandleFunc("", requireAdmin(ctrl.GetUsers)).Methods("GET")
+	users.HandleFunc("/{id}", ctrl.GetUser).Methods("GET")
+	users.HandleFunc("", requireAdmin(ctrl.CreateUser)).Methods("POST")
+	users.HandleFunc("/{id}", ctrl.UpdateUser).Methods("PUT")
+	users.HandleFunc("/{id}", requireAdmin(ctrl.DeleteUser)).Methods("DELETE")
+	users.HandleFunc("/{id}/verify/send", ctrl.SendVerification).Methods("POST")
+
+	// start server
+	log.Fatal(http.ListenAndServe(":8000", jsonContentTypeMiddleware(router)))
+}
+
+// requireAdmin adapts auth.RequireAdmin to wrap a single handler instead of
+// a whole subrouter.
+func requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return auth.RequireAdmin(next).ServeHTTP
+}
+
+func jsonContentTypeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		next.ServeHTTP(w, r)
+	})
+}
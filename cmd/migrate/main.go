@@ -0,0 +1,128 @@
+// Command migrate applies or inspects the users database schema using the
+// SQL files embedded in the migrations package.
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/wess-os/api_golang_pg_docker/internal/auth"
+	"github.com/wess-os/api_golang_pg_docker/migrations"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: migrate up|down|version|seed-admin")
+	}
+
+	// seed-admin talks to the database directly and doesn't need a
+	// migrate.Migrate instance, so it's handled before building one.
+	if os.Args[1] == "seed-admin" {
+		if err := seedAdmin(os.Getenv("DATABASE_URL")); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	m, err := migrations.New(os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch os.Args[1] {
+	case "up":
+		if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			log.Fatal(err)
+		}
+		log.Println("migrations applied")
+	case "down":
+		if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			log.Fatal(err)
+		}
+		log.Println("migrations reverted")
+	case "version":
+		version, dirty, err := m.Version()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("version %d (dirty=%v)\n", version, dirty)
+	default:
+		log.Fatalf("unknown command %q: usage: migrate up|down|version|seed-admin", os.Args[1])
+	}
+}
+
+// seedAdmin bootstraps the first admin account from ADMIN_EMAIL and
+// ADMIN_PASSWORD, creating it pre-verified so it can log in immediately.
+// There is otherwise no HTTP path to produce a usable account on a fresh
+// database: POST /users requires an admin token, and POST /login requires
+// the target account to already be verified. It is a no-op, so it's safe
+// to run on every deploy, if an admin already exists.
+func seedAdmin(databaseURL string) error {
+	email := os.Getenv("ADMIN_EMAIL")
+	password := os.Getenv("ADMIN_PASSWORD")
+	if email == "" || password == "" {
+		return errors.New("seed-admin: ADMIN_EMAIL and ADMIN_PASSWORD must be set")
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	name := os.Getenv("ADMIN_NAME")
+	if name == "" {
+		name = "Admin"
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The table lock (held until commit) serializes the existence check and
+	// insert across concurrent seed-admin runs against a fresh database (e.g.
+	// from parallel deploy/init-container runs), so they can't both observe
+	// "no admin yet" and both insert one.
+	if _, err := tx.Exec("LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO users (name, email, password, role, verified)
+		 SELECT $1, $2, $3, $4, true
+		 WHERE NOT EXISTS (SELECT 1 FROM users WHERE role = $4)`,
+		name, email, string(hashed), auth.RoleAdmin,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if rows == 0 {
+		log.Println("seed-admin: an admin already exists, skipping")
+		return nil
+	}
+
+	log.Println("seed-admin: admin account created")
+	return nil
+}
@@ -0,0 +1,139 @@
+// Package auth issues and validates the JWTs that protect the users API.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Roles recognized by the API.
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+const defaultTTL = 24 * time.Hour
+
+type contextKey string
+
+const userContextKey contextKey = "auth.user"
+
+// User is the minimal subject information embedded in a token.
+type User struct {
+	ID   int
+	Role string
+}
+
+type claims struct {
+	UserID int    `json:"user_id"`
+	Role   string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+func secret() []byte {
+	s := os.Getenv("JWT_SECRET")
+	if s == "" {
+		// Signing or verifying with an empty key would let anyone forge
+		// tokens offline, so fail loudly instead of degrading silently.
+		panic("auth: JWT_SECRET must be set")
+	}
+	return []byte(s)
+}
+
+// ttl reads JWT_TTL (a Go duration string, e.g. "1h") from the environment,
+// falling back to defaultTTL when unset or invalid.
+func ttl() time.Duration {
+	raw := os.Getenv("JWT_TTL")
+	if raw == "" {
+		return defaultTTL
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return defaultTTL
+	}
+	return d
+}
+
+// IssueToken signs a new JWT for u, embedding its id and role.
+func IssueToken(u User) (string, error) {
+	now := time.Now()
+	c := claims{
+		UserID: u.ID,
+		Role:   u.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl())),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
+	return token.SignedString(secret())
+}
+
+// ParseToken validates a signed token and returns the user it was issued for.
+func ParseToken(raw string) (User, error) {
+	c := &claims{}
+	token, err := jwt.ParseWithClaims(raw, c, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("auth: unexpected signing method")
+		}
+		return secret(), nil
+	})
+	if err != nil || !token.Valid {
+		return User{}, errors.New("auth: invalid or expired token")
+	}
+
+	return User{ID: c.UserID, Role: c.Role}, nil
+}
+
+// Middleware validates the Authorization: Bearer <token> header and, on
+// success, stores the authenticated user on the request context.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			http.Error(w, "missing or malformed Authorization header", http.StatusUnauthorized)
+			return
+		}
+
+		u, err := ParseToken(parts[1])
+		if err != nil {
+			http.Error(w, "invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userContextKey, u)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// UserFromContext returns the authenticated user stored by Middleware.
+func UserFromContext(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userContextKey).(User)
+	return u, ok
+}
+
+// NewContextWithUser returns a copy of ctx carrying u, as Middleware would
+// store it. Useful for tests that exercise handlers directly.
+func NewContextWithUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userContextKey, u)
+}
+
+// RequireAdmin wraps next, rejecting any request whose authenticated user is
+// not an admin. It must run after Middleware.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, ok := UserFromContext(r.Context())
+		if !ok || u.Role != RoleAdmin {
+			http.Error(w, "admin role required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
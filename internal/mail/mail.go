@@ -0,0 +1,55 @@
+// Package mail abstracts outbound email delivery so handlers don't depend
+// directly on an SMTP connection.
+package mail
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+)
+
+// Sender delivers a single email to recipient with the given subject and
+// body.
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+// NoopSender discards every message. Use it in tests and any environment
+// where outbound email isn't configured.
+type NoopSender struct{}
+
+// Send implements Sender.
+func (NoopSender) Send(to, subject, body string) error { return nil }
+
+// SMTPSender delivers mail through a configured SMTP server.
+type SMTPSender struct {
+	Host string
+	Port string
+	From string
+	Auth smtp.Auth
+}
+
+// NewSMTPSenderFromEnv builds an SMTPSender from SMTP_HOST, SMTP_PORT,
+// SMTP_FROM, SMTP_USERNAME and SMTP_PASSWORD.
+func NewSMTPSenderFromEnv() *SMTPSender {
+	host := os.Getenv("SMTP_HOST")
+
+	var auth smtp.Auth
+	if user := os.Getenv("SMTP_USERNAME"); user != "" {
+		auth = smtp.PlainAuth("", user, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	return &SMTPSender{
+		Host: host,
+		Port: os.Getenv("SMTP_PORT"),
+		From: os.Getenv("SMTP_FROM"),
+		Auth: auth,
+	}
+}
+
+// Send implements Sender.
+func (s *SMTPSender) Send(to, subject, body string) error {
+	addr := fmt.Sprintf("%s:%s", s.Host, s.Port)
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", s.From, to, subject, body)
+	return smtp.SendMail(addr, s.Auth, s.From, []string{to}, []byte(msg))
+}
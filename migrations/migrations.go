@@ -0,0 +1,37 @@
+// Package migrations embeds the SQL migration files for the users database
+// and exposes helpers to apply them with golang-migrate.
+package migrations
+
+import (
+	"embed"
+	"errors"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+// New returns a migrate.Migrate instance that reads the embedded SQL files
+// and applies them against databaseURL.
+func New(databaseURL string) (*migrate.Migrate, error) {
+	src, err := iofs.New(FS, ".")
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewWithSourceInstance("iofs", src, databaseURL)
+}
+
+// Up applies all pending migrations against databaseURL.
+func Up(databaseURL string) error {
+	m, err := New(databaseURL)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
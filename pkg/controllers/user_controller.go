@@ -0,0 +1,352 @@
+// Package controllers adapts HTTP requests to the user service.
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wess-os/api_golang_pg_docker/internal/auth"
+	"github.com/wess-os/api_golang_pg_docker/internal/mail"
+	"github.com/wess-os/api_golang_pg_docker/pkg/models"
+	"github.com/wess-os/api_golang_pg_docker/pkg/repository"
+	"github.com/wess-os/api_golang_pg_docker/pkg/service"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// usersEnvelope is the JSON shape returned by the offset-paginated branch of
+// GetUsers.
+type usersEnvelope struct {
+	Data   []models.User `json:"data"`
+	Total  int           `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+// keysetUsersEnvelope is the JSON shape returned by the keyset-paginated
+// (?after_id=) branch of GetUsers, which has neither a total count nor an
+// offset to report.
+type keysetUsersEnvelope struct {
+	Data  []models.User `json:"data"`
+	Limit int           `json:"limit"`
+}
+
+// UserController exposes the users API as HTTP handlers backed by a
+// UserService.
+type UserController struct {
+	svc       *service.UserService
+	mailer    mail.Sender
+	verifyURL string
+}
+
+// NewUserController returns a UserController. verifyURL is the externally
+// reachable link (e.g. https://api.example.com/users/verify) embedded in
+// verification emails.
+func NewUserController(svc *service.UserService, mailer mail.Sender, verifyURL string) *UserController {
+	return &UserController{svc: svc, mailer: mailer, verifyURL: verifyURL}
+}
+
+// Login verifies the user's email/password and, on success, issues a JWT.
+func (c *UserController) Login(w http.ResponseWriter, r *http.Request) {
+	var creds struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	token, err := c.svc.Login(creds.Email, creds.Password)
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials):
+		respondWithError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrNotVerified):
+		respondWithError(w, http.StatusForbidden, err.Error())
+	case err != nil:
+		log.Printf("Login: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error issuing token")
+	default:
+		respondWithJSON(w, http.StatusOK, map[string]string{"token": token})
+	}
+}
+
+// GetUsers lists users, honoring ?limit=, ?offset=, ?sort=, ?order=, ?q= and
+// the keyset alternative ?after_id=.
+func (c *UserController) GetUsers(w http.ResponseWriter, r *http.Request) {
+	limit := parseLimit(r.URL.Query().Get("limit"))
+	q := r.URL.Query().Get("q")
+
+	if after := r.URL.Query().Get("after_id"); after != "" {
+		afterID, err := strconv.Atoi(after)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "after_id must be an integer")
+			return
+		}
+
+		users, err := c.svc.ListAfter(afterID, limit, q)
+		if err != nil {
+			log.Printf("GetUsers: %v", err)
+			respondWithError(w, http.StatusInternalServerError, "Error fetching users")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, keysetUsersEnvelope{Data: users, Limit: limit})
+		return
+	}
+
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter := repository.ListFilter{
+		Limit:  limit,
+		Offset: offset,
+		Sort:   r.URL.Query().Get("sort"),
+		Order:  r.URL.Query().Get("order"),
+		Query:  q,
+	}
+
+	users, total, err := c.svc.List(filter)
+	if err != nil {
+		log.Printf("GetUsers: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error fetching users")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, usersEnvelope{Data: users, Total: total, Limit: limit, Offset: offset})
+}
+
+// parseLimit clamps the requested ?limit= to (0, maxLimit], defaulting to
+// defaultLimit when absent or invalid.
+func parseLimit(raw string) int {
+	if raw == "" {
+		return defaultLimit
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return defaultLimit
+	}
+	if limit > maxLimit {
+		return maxLimit
+	}
+	return limit
+}
+
+// GetUser fetches a single user; a non-admin may only fetch their own record.
+func (c *UserController) GetUser(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !c.canAccess(r, id) {
+		respondWithError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	numericID, err := strconv.Atoi(id)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user id")
+		return
+	}
+
+	u, err := c.svc.Get(numericID)
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		respondWithError(w, http.StatusNotFound, "User not found")
+	case err != nil:
+		log.Printf("GetUser: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error fetching user")
+	default:
+		respondWithJSON(w, http.StatusOK, u)
+	}
+}
+
+// CreateUser creates a new user; only admins may call this.
+func (c *UserController) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var u models.User
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if u.Name == "" || !isValidEmail(u.Email) {
+		respondWithError(w, http.StatusBadRequest, "Invalid input: Name is required and Email must be valid")
+		return
+	}
+	if u.Password == "" {
+		respondWithError(w, http.StatusBadRequest, "Invalid input: Password is required")
+		return
+	}
+
+	created, err := c.svc.Create(u)
+	switch {
+	case errors.Is(err, service.ErrAlreadyExists):
+		respondWithError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		log.Printf("CreateUser: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error creating user")
+	default:
+		respondWithJSON(w, http.StatusCreated, created)
+	}
+}
+
+// UpdateUser updates name/email for a user; only an admin may also change
+// the role.
+func (c *UserController) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	var u models.User
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if u.Name == "" || u.Email == "" {
+		respondWithError(w, http.StatusBadRequest, "Name and Email are required")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if !c.canAccess(r, id) {
+		respondWithError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	numericID, err := strconv.Atoi(id)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user id")
+		return
+	}
+
+	var role *string
+	if u.Role != "" {
+		caller, _ := auth.UserFromContext(r.Context())
+		if caller.Role != auth.RoleAdmin {
+			respondWithError(w, http.StatusForbidden, "Only an admin may change a user's role")
+			return
+		}
+		role = &u.Role
+	}
+
+	updated, err := c.svc.Update(numericID, u.Name, u.Email, role)
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		respondWithError(w, http.StatusNotFound, "User not found")
+	case errors.Is(err, service.ErrEmailInUse):
+		respondWithError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrNoChanges):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case err != nil:
+		log.Printf("UpdateUser: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error updating user")
+	default:
+		respondWithJSON(w, http.StatusOK, updated)
+	}
+}
+
+// DeleteUser removes a user; only admins may call this.
+func (c *UserController) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	numericID, err := strconv.Atoi(id)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user id")
+		return
+	}
+
+	err = c.svc.Delete(numericID)
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		respondWithError(w, http.StatusNotFound, "User not found")
+	case err != nil:
+		log.Printf("DeleteUser: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error deleting user")
+	default:
+		respondWithJSON(w, http.StatusOK, map[string]string{"message": "User deleted"})
+	}
+}
+
+// SendVerification generates and emails a single-use verification token for
+// the given user; only an admin or the user themselves may call this.
+func (c *UserController) SendVerification(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !c.canAccess(r, id) {
+		respondWithError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	numericID, err := strconv.Atoi(id)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user id")
+		return
+	}
+
+	err = c.svc.SendVerification(numericID, c.mailer, c.verifyURL)
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		respondWithError(w, http.StatusNotFound, "User not found")
+	case err != nil:
+		log.Printf("SendVerification: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error sending verification email")
+	default:
+		respondWithJSON(w, http.StatusOK, map[string]string{"message": "Verification email sent"})
+	}
+}
+
+// VerifyUser marks the user owning the given token as verified.
+func (c *UserController) VerifyUser(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		respondWithError(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	err := c.svc.Verify(token)
+	switch {
+	case errors.Is(err, service.ErrInvalidToken):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case err != nil:
+		log.Printf("VerifyUser: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error verifying token")
+	default:
+		respondWithJSON(w, http.StatusOK, map[string]string{"message": "Account verified"})
+	}
+}
+
+// canAccess reports whether the authenticated user on r may act on the user
+// identified by id: admins may act on anyone, everyone else only on
+// themselves.
+func (c *UserController) canAccess(r *http.Request, id string) bool {
+	u, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		return false
+	}
+	if u.Role == auth.RoleAdmin {
+		return true
+	}
+	return strconv.Itoa(u.ID) == id
+}
+
+// isValidEmail verify if the email is valid
+func isValidEmail(email string) bool {
+	re := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	return re.MatchString(email)
+}
+
+// respondWithError writes a JSON error body of the form {"error": message}
+// with the given status code.
+func respondWithError(w http.ResponseWriter, code int, message string) {
+	respondWithJSON(w, code, map[string]string{"error": message})
+}
+
+// respondWithJSON marshals payload as the response body with the given
+// status code.
+func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.Printf("respondWithJSON: failed to encode response: %v", err)
+	}
+}
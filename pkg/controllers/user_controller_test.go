@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wess-os/api_golang_pg_docker/internal/auth"
+	"github.com/wess-os/api_golang_pg_docker/internal/mail"
+	"github.com/wess-os/api_golang_pg_docker/pkg/models"
+	"github.com/wess-os/api_golang_pg_docker/pkg/repository"
+	"github.com/wess-os/api_golang_pg_docker/pkg/service"
+)
+
+// fakeUserRepo is a minimal in-memory repository.UserRepository, just
+// enough to exercise the controller without a database.
+type fakeUserRepo struct {
+	users map[int]models.User
+}
+
+func (f *fakeUserRepo) List(repository.ListFilter) ([]models.User, int, error) { return nil, 0, nil }
+func (f *fakeUserRepo) ListAfter(int, int, string) ([]models.User, error)      { return nil, nil }
+
+func (f *fakeUserRepo) GetByID(id int) (models.User, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return models.User{}, sql.ErrNoRows
+	}
+	return u, nil
+}
+
+func (f *fakeUserRepo) GetByEmail(string) (models.User, error)           { return models.User{}, sql.ErrNoRows }
+func (f *fakeUserRepo) ExistsByNameOrEmail(string, string) (bool, error) { return false, nil }
+func (f *fakeUserRepo) EmailInUseByOther(string, int) (bool, error)      { return false, nil }
+func (f *fakeUserRepo) Create(u models.User) (models.User, error)        { return u, nil }
+func (f *fakeUserRepo) Update(int, string, string, *string) error        { return nil }
+func (f *fakeUserRepo) Delete(int) error                                 { return nil }
+func (f *fakeUserRepo) CreateVerification(string, int, time.Time) error  { return nil }
+func (f *fakeUserRepo) UserIDForVerification(string) (int, error)        { return 0, sql.ErrNoRows }
+func (f *fakeUserRepo) MarkVerified(int) error                           { return nil }
+func (f *fakeUserRepo) DeleteVerification(string) error                  { return nil }
+
+func withUser(r *http.Request, u auth.User) *http.Request {
+	return r.WithContext(auth.NewContextWithUser(r.Context(), u))
+}
+
+func TestUserControllerGetUser(t *testing.T) {
+	repo := &fakeUserRepo{users: map[int]models.User{
+		1: {ID: 1, Name: "Ada", Email: "ada@example.com", Role: auth.RoleUser},
+	}}
+	ctrl := NewUserController(service.NewUserService(repo), mail.NoopSender{}, "https://example.com/users/verify")
+
+	cases := []struct {
+		name       string
+		id         string
+		caller     auth.User
+		wantStatus int
+	}{
+		{name: "found", id: "1", caller: auth.User{ID: 1, Role: auth.RoleUser}, wantStatus: http.StatusOK},
+		{name: "not found", id: "99", caller: auth.User{ID: 99, Role: auth.RoleUser}, wantStatus: http.StatusNotFound},
+		{name: "forbidden for another user's record", id: "1", caller: auth.User{ID: 2, Role: auth.RoleUser}, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/"+tc.id, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.id})
+			req = withUser(req, tc.caller)
+			rr := httptest.NewRecorder()
+
+			ctrl.GetUser(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Errorf("got status %d, want %d (body: %s)", rr.Code, tc.wantStatus, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestUserControllerCreateUserValidation(t *testing.T) {
+	ctrl := NewUserController(service.NewUserService(&fakeUserRepo{users: map[int]models.User{}}), mail.NoopSender{}, "")
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	rr := httptest.NewRecorder()
+
+	ctrl.CreateUser(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,12 @@
+// Package models holds the domain types shared across the API's layers.
+package models
+
+// User is an account in the system.
+type User struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password,omitempty"`
+	Role     string `json:"role"`
+	Verified bool   `json:"verified"`
+}
@@ -0,0 +1,85 @@
+//go:build integration
+
+// These tests exercise postgresUserRepo.List and ListAfter against a real
+// Postgres instance and are gated behind the "integration" build tag since
+// they need DATABASE_URL to point at a running database (e.g. the one from
+// docker-compose).
+package repository
+
+import (
+	"database/sql"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/wess-os/api_golang_pg_docker/migrations"
+)
+
+func setupIntegrationDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL not set; skipping integration test")
+	}
+
+	if err := migrations.Up(databaseURL); err != nil {
+		t.Fatalf("migrations.Up: %v", err)
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec("DELETE FROM users"); err != nil {
+		t.Fatalf("cleaning users table: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		n := strconv.Itoa(i)
+		_, err := db.Exec(
+			"INSERT INTO users (name, email, password, role) VALUES ($1, $2, 'x', 'user')",
+			"user"+n, "user"+n+"@example.com",
+		)
+		if err != nil {
+			t.Fatalf("seeding users: %v", err)
+		}
+	}
+
+	return db
+}
+
+func TestIntegrationListOffsetPagination(t *testing.T) {
+	db := setupIntegrationDB(t)
+	repo := NewPostgresUserRepo(db)
+
+	users, total, err := repo.List(ListFilter{Limit: 2, Offset: 1, Sort: "name", Order: "asc"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 5 {
+		t.Errorf("got total %d, want 5", total)
+	}
+	if len(users) != 2 {
+		t.Errorf("got %d users, want 2", len(users))
+	}
+}
+
+func TestIntegrationListAfterKeysetPagination(t *testing.T) {
+	db := setupIntegrationDB(t)
+	repo := NewPostgresUserRepo(db)
+
+	users, err := repo.ListAfter(1, 2, "")
+	if err != nil {
+		t.Fatalf("ListAfter: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("got %d users, want 2", len(users))
+	}
+	for _, u := range users {
+		if u.ID <= 1 {
+			t.Errorf("got user id %d, want > 1", u.ID)
+		}
+	}
+}
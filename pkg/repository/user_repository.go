@@ -0,0 +1,207 @@
+// Package repository provides SQL-backed access to the users table.
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wess-os/api_golang_pg_docker/pkg/models"
+)
+
+// ListFilter narrows, sorts and paginates an offset-based user listing.
+type ListFilter struct {
+	Limit  int
+	Offset int
+	Sort   string // "id", "name" or "email"; invalid values fall back to "id"
+	Order  string // "asc" or "desc"; anything else means ascending
+	Query  string // substring matched against name/email
+}
+
+var sortColumns = map[string]string{
+	"id":    "id",
+	"name":  "name",
+	"email": "email",
+}
+
+// UserRepository is the persistence seam for users. postgresUserRepo is the
+// real implementation; tests can supply an in-memory fake instead so the
+// service layer can be exercised without a database.
+type UserRepository interface {
+	List(f ListFilter) ([]models.User, int, error)
+	ListAfter(afterID, limit int, query string) ([]models.User, error)
+	GetByID(id int) (models.User, error)
+	GetByEmail(email string) (models.User, error)
+	ExistsByNameOrEmail(name, email string) (bool, error)
+	EmailInUseByOther(email string, id int) (bool, error)
+	Create(u models.User) (models.User, error)
+	Update(id int, name, email string, role *string) error
+	Delete(id int) error
+	CreateVerification(token string, userID int, expiresAt time.Time) error
+	UserIDForVerification(token string) (int, error)
+	MarkVerified(id int) error
+	DeleteVerification(token string) error
+}
+
+type postgresUserRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresUserRepo returns a UserRepository backed by db.
+func NewPostgresUserRepo(db *sql.DB) UserRepository {
+	return &postgresUserRepo{db: db}
+}
+
+func (r *postgresUserRepo) List(f ListFilter) ([]models.User, int, error) {
+	column, ok := sortColumns[f.Sort]
+	if !ok {
+		column = "id"
+	}
+	order := "ASC"
+	if strings.EqualFold(f.Order, "desc") {
+		order = "DESC"
+	}
+
+	query := fmt.Sprintf(
+		`SELECT id, name, email, role, verified, COUNT(*) OVER() AS total FROM users
+		 WHERE ($1 = '' OR name ILIKE '%%' || $1 || '%%' OR email ILIKE '%%' || $1 || '%%')
+		 ORDER BY %s %s LIMIT $2 OFFSET $3`,
+		column, order,
+	)
+
+	rows, err := r.db.Query(query, f.Query, f.Limit, f.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	total := 0
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.Verified, &total); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// ListAfter lists users with id > afterID, ordered by id, for stable
+// iteration over large tables without the cost of an OFFSET scan.
+func (r *postgresUserRepo) ListAfter(afterID, limit int, query string) ([]models.User, error) {
+	rows, err := r.db.Query(
+		`SELECT id, name, email, role, verified FROM users
+		 WHERE id > $1 AND ($2 = '' OR name ILIKE '%' || $2 || '%' OR email ILIKE '%' || $2 || '%')
+		 ORDER BY id ASC LIMIT $3`,
+		afterID, query, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.Verified); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (r *postgresUserRepo) GetByID(id int) (models.User, error) {
+	var u models.User
+	err := r.db.QueryRow("SELECT id, name, email, role, verified FROM users WHERE id = $1", id).
+		Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.Verified)
+	return u, err
+}
+
+func (r *postgresUserRepo) GetByEmail(email string) (models.User, error) {
+	var u models.User
+	err := r.db.QueryRow("SELECT id, name, email, password, role, verified FROM users WHERE email = $1", email).
+		Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.Verified)
+	return u, err
+}
+
+func (r *postgresUserRepo) ExistsByNameOrEmail(name, email string) (bool, error) {
+	var id int
+	err := r.db.QueryRow("SELECT id FROM users WHERE name = $1 OR email = $2", name, email).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *postgresUserRepo) EmailInUseByOther(email string, id int) (bool, error) {
+	var existingID int
+	err := r.db.QueryRow("SELECT id FROM users WHERE email = $1 AND id != $2", email, id).Scan(&existingID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *postgresUserRepo) Create(u models.User) (models.User, error) {
+	err := r.db.QueryRow(
+		"INSERT INTO users (name, email, password, role) VALUES ($1, $2, $3, $4) RETURNING id",
+		u.Name, u.Email, u.Password, u.Role,
+	).Scan(&u.ID)
+	return u, err
+}
+
+func (r *postgresUserRepo) Update(id int, name, email string, role *string) error {
+	if role != nil {
+		_, err := r.db.Exec("UPDATE users SET name = $1, email = $2, role = $3 WHERE id = $4", name, email, *role, id)
+		return err
+	}
+	_, err := r.db.Exec("UPDATE users SET name = $1, email = $2 WHERE id = $3", name, email, id)
+	return err
+}
+
+func (r *postgresUserRepo) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *postgresUserRepo) CreateVerification(token string, userID int, expiresAt time.Time) error {
+	_, err := r.db.Exec(
+		"INSERT INTO user_verifications (token, user_id, expires_at) VALUES ($1, $2, $3)",
+		token, userID, expiresAt,
+	)
+	return err
+}
+
+func (r *postgresUserRepo) UserIDForVerification(token string) (int, error) {
+	var userID int
+	err := r.db.QueryRow(
+		"SELECT user_id FROM user_verifications WHERE token = $1 AND expires_at > now()", token,
+	).Scan(&userID)
+	return userID, err
+}
+
+func (r *postgresUserRepo) MarkVerified(id int) error {
+	_, err := r.db.Exec("UPDATE users SET verified = true WHERE id = $1", id)
+	return err
+}
+
+func (r *postgresUserRepo) DeleteVerification(token string) error {
+	_, err := r.db.Exec("DELETE FROM user_verifications WHERE token = $1", token)
+	return err
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPostgresUserRepoGetByID(t *testing.T) {
+	cases := []struct {
+		name    string
+		mock    func(mock sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "found",
+			mock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name", "email", "role", "verified"}).
+					AddRow(1, "Ada", "ada@example.com", "user", true)
+				mock.ExpectQuery("SELECT id, name, email, role, verified FROM users WHERE id = \\$1").
+					WithArgs(1).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "not found",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT id, name, email, role, verified FROM users WHERE id = \\$1").
+					WithArgs(1).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("sqlmock.New: %v", err)
+			}
+			defer db.Close()
+			tc.mock(mock)
+
+			repo := NewPostgresUserRepo(db)
+			_, err = repo.GetByID(1)
+			if err != tc.wantErr {
+				t.Errorf("got err %v, want %v", err, tc.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresUserRepoList(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "role", "verified", "total"}).
+		AddRow(1, "Ada", "ada@example.com", "admin", true, 2).
+		AddRow(2, "Grace", "grace@example.com", "user", false, 2)
+	mock.ExpectQuery("SELECT id, name, email, role, verified, COUNT").
+		WithArgs("", 20, 0).WillReturnRows(rows)
+
+	repo := NewPostgresUserRepo(db)
+	users, total, err := repo.List(ListFilter{Limit: 20, Offset: 0})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(users) != 2 || total != 2 {
+		t.Errorf("got %d users, total %d; want 2, 2", len(users), total)
+	}
+}
+
+func TestPostgresUserRepoDelete(t *testing.T) {
+	cases := []struct {
+		name    string
+		result  driver.Result
+		wantErr error
+	}{
+		{name: "deleted", result: sqlmock.NewResult(0, 1)},
+		{name: "not found", result: sqlmock.NewResult(0, 0), wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("sqlmock.New: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectExec("DELETE FROM users WHERE id = \\$1").WithArgs(1).WillReturnResult(tc.result)
+
+			repo := NewPostgresUserRepo(db)
+			if err := repo.Delete(1); err != tc.wantErr {
+				t.Errorf("got err %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPostgresUserRepoVerificationLifecycle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO user_verifications").
+		WithArgs("tok123", 1, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT user_id FROM user_verifications WHERE token = \\$1").
+		WithArgs("tok123").WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+	mock.ExpectExec("UPDATE users SET verified = true WHERE id = \\$1").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM user_verifications WHERE token = \\$1").
+		WithArgs("tok123").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewPostgresUserRepo(db)
+	if err := repo.CreateVerification("tok123", 1, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("CreateVerification: %v", err)
+	}
+	userID, err := repo.UserIDForVerification("tok123")
+	if err != nil || userID != 1 {
+		t.Fatalf("UserIDForVerification: got (%d, %v), want (1, nil)", userID, err)
+	}
+	if err := repo.MarkVerified(userID); err != nil {
+		t.Fatalf("MarkVerified: %v", err)
+	}
+	if err := repo.DeleteVerification("tok123"); err != nil {
+		t.Fatalf("DeleteVerification: %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+// Package service implements the business rules for managing users:
+// validation, uniqueness, password hashing and the login/verification flows.
+package service
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/wess-os/api_golang_pg_docker/internal/auth"
+	"github.com/wess-os/api_golang_pg_docker/internal/mail"
+	"github.com/wess-os/api_golang_pg_docker/pkg/models"
+	"github.com/wess-os/api_golang_pg_docker/pkg/repository"
+)
+
+// verificationTTL is how long a verification token remains valid.
+const verificationTTL = 24 * time.Hour
+
+var (
+	ErrNotFound           = errors.New("user not found")
+	ErrAlreadyExists      = errors.New("user with the same name or email already exists")
+	ErrEmailInUse         = errors.New("email already in use by another user")
+	ErrNoChanges          = errors.New("no changes detected")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrNotVerified        = errors.New("email not verified")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+)
+
+// UserService implements the business rules around user accounts on top of
+// a UserRepository.
+type UserService struct {
+	repo repository.UserRepository
+}
+
+// NewUserService returns a UserService backed by repo.
+func NewUserService(repo repository.UserRepository) *UserService {
+	return &UserService{repo: repo}
+}
+
+// List returns a page of users matching f, along with the total count.
+func (s *UserService) List(f repository.ListFilter) ([]models.User, int, error) {
+	return s.repo.List(f)
+}
+
+// ListAfter returns up to limit users with id > afterID, for keyset
+// pagination over large tables.
+func (s *UserService) ListAfter(afterID, limit int, query string) ([]models.User, error) {
+	return s.repo.ListAfter(afterID, limit, query)
+}
+
+// Get returns the user identified by id.
+func (s *UserService) Get(id int) (models.User, error) {
+	u, err := s.repo.GetByID(id)
+	if err == sql.ErrNoRows {
+		return models.User{}, ErrNotFound
+	}
+	return u, err
+}
+
+// Create validates and persists a new user, hashing its password and
+// rejecting a name/email that's already taken.
+func (s *UserService) Create(u models.User) (models.User, error) {
+	if u.Role == "" {
+		u.Role = auth.RoleUser
+	}
+
+	exists, err := s.repo.ExistsByNameOrEmail(u.Name, u.Email)
+	if err != nil {
+		return models.User{}, err
+	}
+	if exists {
+		return models.User{}, ErrAlreadyExists
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return models.User{}, err
+	}
+	u.Password = string(hashed)
+
+	created, err := s.repo.Create(u)
+	if err != nil {
+		return models.User{}, err
+	}
+	created.Password = ""
+	return created, nil
+}
+
+// Update applies a name/email change (and a role change, when role is
+// non-nil) to user id. It rejects no-op updates and email collisions.
+func (s *UserService) Update(id int, name, email string, role *string) (models.User, error) {
+	inUse, err := s.repo.EmailInUseByOther(email, id)
+	if err != nil {
+		return models.User{}, err
+	}
+	if inUse {
+		return models.User{}, ErrEmailInUse
+	}
+
+	current, err := s.repo.GetByID(id)
+	if err == sql.ErrNoRows {
+		return models.User{}, ErrNotFound
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if current.Name == name && current.Email == email && role == nil {
+		return models.User{}, ErrNoChanges
+	}
+
+	if err := s.repo.Update(id, name, email, role); err != nil {
+		return models.User{}, err
+	}
+
+	current.Name = name
+	current.Email = email
+	if role != nil {
+		current.Role = *role
+	}
+	return current, nil
+}
+
+// Delete removes the user identified by id.
+func (s *UserService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Login verifies credentials and issues a JWT for a verified user.
+func (s *UserService) Login(email, password string) (string, error) {
+	u, err := s.repo.GetByEmail(email)
+	if err == sql.ErrNoRows {
+		return "", ErrInvalidCredentials
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
+		return "", ErrInvalidCredentials
+	}
+	if !u.Verified {
+		return "", ErrNotVerified
+	}
+
+	return auth.IssueToken(auth.User{ID: u.ID, Role: u.Role})
+}
+
+// SendVerification generates a single-use verification token for the user
+// and emails a verification link built from verifyURL.
+func (s *UserService) SendVerification(id int, sender mail.Sender, verifyURL string) error {
+	u, err := s.repo.GetByID(id)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := generateVerificationToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.CreateVerification(token, id, time.Now().Add(verificationTTL)); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Verify your account: %s?token=%s", verifyURL, token)
+	return sender.Send(u.Email, "Verify your account", body)
+}
+
+// Verify marks the user owning token as verified.
+func (s *UserService) Verify(token string) error {
+	userID, err := s.repo.UserIDForVerification(token)
+	if err == sql.ErrNoRows {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.MarkVerified(userID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteVerification(token)
+}
+
+// generateVerificationToken returns a random 32-byte base64url token.
+func generateVerificationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(b), nil
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/wess-os/api_golang_pg_docker/internal/mail"
+	"github.com/wess-os/api_golang_pg_docker/pkg/models"
+	"github.com/wess-os/api_golang_pg_docker/pkg/repository"
+)
+
+// fakeUserRepo is an in-memory repository.UserRepository used to exercise
+// UserService's business rules without a database.
+type fakeUserRepo struct {
+	users         map[int]models.User
+	nextID        int
+	verifications map[string]int
+}
+
+func newFakeUserRepo() *fakeUserRepo {
+	return &fakeUserRepo{users: map[int]models.User{}, nextID: 1, verifications: map[string]int{}}
+}
+
+func (f *fakeUserRepo) List(repository.ListFilter) ([]models.User, int, error) { return nil, 0, nil }
+func (f *fakeUserRepo) ListAfter(int, int, string) ([]models.User, error)      { return nil, nil }
+
+func (f *fakeUserRepo) GetByID(id int) (models.User, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return models.User{}, sql.ErrNoRows
+	}
+	return u, nil
+}
+
+func (f *fakeUserRepo) GetByEmail(email string) (models.User, error) {
+	for _, u := range f.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return models.User{}, sql.ErrNoRows
+}
+
+func (f *fakeUserRepo) ExistsByNameOrEmail(name, email string) (bool, error) {
+	for _, u := range f.users {
+		if u.Name == name || u.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeUserRepo) EmailInUseByOther(email string, id int) (bool, error) {
+	for _, u := range f.users {
+		if u.Email == email && u.ID != id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeUserRepo) Create(u models.User) (models.User, error) {
+	u.ID = f.nextID
+	f.nextID++
+	f.users[u.ID] = u
+	return u, nil
+}
+
+func (f *fakeUserRepo) Update(id int, name, email string, role *string) error {
+	u, ok := f.users[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	u.Name = name
+	u.Email = email
+	if role != nil {
+		u.Role = *role
+	}
+	f.users[id] = u
+	return nil
+}
+
+func (f *fakeUserRepo) Delete(id int) error {
+	if _, ok := f.users[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(f.users, id)
+	return nil
+}
+
+func (f *fakeUserRepo) CreateVerification(token string, userID int, expiresAt time.Time) error {
+	f.verifications[token] = userID
+	return nil
+}
+
+func (f *fakeUserRepo) UserIDForVerification(token string) (int, error) {
+	id, ok := f.verifications[token]
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+	return id, nil
+}
+
+func (f *fakeUserRepo) MarkVerified(id int) error {
+	u := f.users[id]
+	u.Verified = true
+	f.users[id] = u
+	return nil
+}
+
+func (f *fakeUserRepo) DeleteVerification(token string) error {
+	delete(f.verifications, token)
+	return nil
+}
+
+func TestUserServiceCreateRejectsDuplicates(t *testing.T) {
+	repo := newFakeUserRepo()
+	svc := NewUserService(repo)
+
+	if _, err := svc.Create(models.User{Name: "Ada", Email: "ada@example.com", Password: "secret"}); err != nil {
+		t.Fatalf("first Create: %v", err)
+	}
+
+	_, err := svc.Create(models.User{Name: "Ada", Email: "other@example.com", Password: "secret"})
+	if err != ErrAlreadyExists {
+		t.Errorf("got err %v, want %v", err, ErrAlreadyExists)
+	}
+}
+
+func TestUserServiceCreateHashesPassword(t *testing.T) {
+	repo := newFakeUserRepo()
+	svc := NewUserService(repo)
+
+	u, err := svc.Create(models.User{Name: "Ada", Email: "ada@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if u.Password != "" {
+		t.Errorf("Create response leaked password hash")
+	}
+	if stored := repo.users[u.ID].Password; stored == "secret" {
+		t.Errorf("password was stored in plaintext")
+	}
+}
+
+func TestUserServiceUpdateRejectsNoChanges(t *testing.T) {
+	repo := newFakeUserRepo()
+	repo.users[1] = models.User{ID: 1, Name: "Ada", Email: "ada@example.com"}
+
+	svc := NewUserService(repo)
+	if _, err := svc.Update(1, "Ada", "ada@example.com", nil); err != ErrNoChanges {
+		t.Errorf("got err %v, want %v", err, ErrNoChanges)
+	}
+}
+
+func TestUserServiceUpdateRejectsEmailInUse(t *testing.T) {
+	repo := newFakeUserRepo()
+	repo.users[1] = models.User{ID: 1, Name: "Ada", Email: "ada@example.com"}
+	repo.users[2] = models.User{ID: 2, Name: "Grace", Email: "grace@example.com"}
+
+	svc := NewUserService(repo)
+	if _, err := svc.Update(1, "Ada", "grace@example.com", nil); err != ErrEmailInUse {
+		t.Errorf("got err %v, want %v", err, ErrEmailInUse)
+	}
+}
+
+func TestUserServiceLogin(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	repo := newFakeUserRepo()
+	svc := NewUserService(repo)
+	created, err := svc.Create(models.User{Name: "Ada", Email: "ada@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if _, err := svc.Login("ada@example.com", "secret"); err != ErrNotVerified {
+		t.Fatalf("got err %v, want %v", err, ErrNotVerified)
+	}
+
+	if err := repo.MarkVerified(created.ID); err != nil {
+		t.Fatalf("MarkVerified: %v", err)
+	}
+
+	if _, err := svc.Login("ada@example.com", "wrong"); err != ErrInvalidCredentials {
+		t.Errorf("got err %v, want %v", err, ErrInvalidCredentials)
+	}
+
+	if _, err := svc.Login("ada@example.com", "secret"); err != nil {
+		t.Errorf("Login: %v", err)
+	}
+}
+
+func TestUserServiceVerify(t *testing.T) {
+	repo := newFakeUserRepo()
+	svc := NewUserService(repo)
+	created, err := svc.Create(models.User{Name: "Ada", Email: "ada@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := svc.SendVerification(created.ID, mail.NoopSender{}, "https://example.com/users/verify"); err != nil {
+		t.Fatalf("SendVerification: %v", err)
+	}
+
+	var token string
+	for tok := range repo.verifications {
+		token = tok
+	}
+
+	if err := svc.Verify(token); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !repo.users[created.ID].Verified {
+		t.Errorf("user was not marked verified")
+	}
+
+	if err := svc.Verify(token); err != ErrInvalidToken {
+		t.Errorf("re-verifying a spent token: got err %v, want %v", err, ErrInvalidToken)
+	}
+}